@@ -3,7 +3,10 @@ package disgord
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -55,58 +58,356 @@ func (ap *ActivityParty) NumberOfPeople() int {
 	return ap.Size[0]
 }
 
+const (
+	defaultActivityAssetImageSize = 1024
+	minActivityAssetImageSize     = 16
+	maxActivityAssetImageSize     = 4096
+)
+
 type ActivityAssets struct {
 	LargeImage string `json:"large_image,omitempty"` // the id for a large asset of the activity, usually a snowflake
 	LargeText  string `json:"large_text,omitempty"`  //text displayed when hovering over the large image of the activity
 	SmallImage string `json:"small_image,omitempty"` // the id for a small asset of the activity, usually a snowflake
 	SmallText  string `json:"small_text,omitempty"`  //	text displayed when hovering over the small image of the activity
 }
+
+// resolveActivityAssetImageURL turns an ActivityAssets image id into a CDN URL. image
+// may be a bare application asset snowflake, or be prefixed with "mp:" (MediaProxy) or
+// "spotify:" to point at an externally hosted image. size must be a power of two between
+// 16 and 4096; any other size is replaced by defaultActivityAssetImageSize (it is not
+// clamped to the nearest bound). size only affects application asset URLs.
+func resolveActivityAssetImageURL(image string, applicationID Snowflake, size int) string {
+	if image == "" {
+		return ""
+	}
+	if size < minActivityAssetImageSize || size > maxActivityAssetImageSize || size&(size-1) != 0 {
+		size = defaultActivityAssetImageSize
+	}
+
+	switch {
+	case strings.HasPrefix(image, "mp:"):
+		return "https://media.discordapp.net/" + strings.TrimPrefix(image, "mp:")
+	case strings.HasPrefix(image, "spotify:"):
+		return "https://i.scdn.co/image/" + strings.TrimPrefix(image, "spotify:")
+	default:
+		return cdnBaseURL + "/app-assets/" + applicationID.String() + "/" + image + ".png?size=" + strconv.Itoa(size)
+	}
+}
+
 type ActivitySecrets struct {
 	Join     string `json:"join,omitempty"`     // the secret for joining a party
 	Spectate string `json:"spectate,omitempty"` // the secret for spectating a game
 	Match    string `json:"match,omitempty"`    // the secret for a specific instanced match
 }
+
+// ActivityType describes what kind of activity a UserActivity represents, and changes
+// how Discord clients render it (e.g. "Playing", "Streaming", "Listening to").
+type ActivityType int
+
+const (
+	ActivityGame ActivityType = iota
+	ActivityStreaming
+	ActivityListening
+	ActivityWatching
+	ActivityCustom
+	ActivityCompeting
+)
+
+// ActivityEmoji is the emoji used for a Custom Status activity.
+type ActivityEmoji struct {
+	Name     string    `json:"name"`
+	ID       Snowflake `json:"id,omitempty"`
+	Animated bool      `json:"animated,omitempty"`
+}
+
+// ActivityButton is one of up to two clickable buttons shown on a rich presence.
+type ActivityButton struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// unixMilliToTime converts a Discord unix-millisecond timestamp to a time.Time. A zero
+// input maps to the zero time, so omitempty on the millisecond wire value round-trips.
+func unixMilliToTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// timeToUnixMilli is the inverse of unixMilliToTime.
+func timeToUnixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
 type ActivityTimestamp struct {
-	Start int `json:"start,omitempty"` // unix time (in milliseconds) of when the activity started
-	End   int `json:"end,omitempty"`   // unix time (in milliseconds) of when the activity ends
+	Start time.Time `json:"-"` // when the activity started
+	End   time.Time `json:"-"` // when the activity ends
+}
+
+type activityTimestampJSON struct {
+	Start int64 `json:"start,omitempty"` // unix time (in milliseconds) of when the activity started
+	End   int64 `json:"end,omitempty"`   // unix time (in milliseconds) of when the activity ends
+}
+
+func (t *ActivityTimestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&activityTimestampJSON{
+		Start: timeToUnixMilli(t.Start),
+		End:   timeToUnixMilli(t.End),
+	})
+}
+
+func (t *ActivityTimestamp) UnmarshalJSON(data []byte) error {
+	var v activityTimestampJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	t.Start = unixMilliToTime(v.Start)
+	t.End = unixMilliToTime(v.End)
+	return nil
 }
 
 // UserActivity https://discordapp.com/developers/docs/topics/gateway#activity-object-activity-structure
 type UserActivity struct {
 	Name          string               `json:"name"`                     // the activity's name
-	Type          int                  `json:"type"`                     // activity type
+	Type          ActivityType         `json:"type"`                     // activity type
 	URL           *string              `json:"url,omitempty"`            //stream url, is validated when type is 1
+	CreatedAt     time.Time            `json:"-"`                        // when the activity was added to the user's session
 	Timestamps    []*ActivityTimestamp `json:"timestamps,omitempty"`     // timestamps object	unix timestamps for start and/or end of the game
 	ApplicationID Snowflake            `json:"application_id,omitempty"` //?	snowflake	application id for the game
 	Details       *string              `json:"details,omitempty"`        //?	?string	what the player is currently doing
 	State         *string              `json:"state,omitempty"`          //state?	?string	the user's current party status
+	Emoji         *ActivityEmoji       `json:"emoji,omitempty"`          // emoji?	emoji object	the emoji used for a custom status
 	Party         *ActivityParty       `json:"party"`                    //party?	party object	information for the current party of the player
 	Assets        *ActivityAssets      `json:"assets,omitempty"`         // assets?	assets object	images for the presence and their hover texts
 	Secrets       *ActivitySecrets     `json:"secrets,omitempty"`        // secrets?	secrets object	secrets for Rich Presence joining and spectating
 	Instance      bool                 `json:"instance,omitempty"`       // instance?	boolean	whether or not the activity is an instanced game session
 	Flags         int                  `json:"flags,omitempty"`          // flags?	int	activity flags ORd together, describes what the payload includes
+	Buttons       []ActivityButton     `json:"buttons,omitempty"`        // buttons?	array of buttons	custom buttons shown in the Rich Presence (max 2)
+}
+
+// LargeImageURL resolves Assets.LargeImage into a CDN URL. size must be a power of two
+// between 16 and 4096; an invalid size falls back to a sensible default.
+func (a *UserActivity) LargeImageURL(size int) string {
+	if a.Assets == nil {
+		return ""
+	}
+	return resolveActivityAssetImageURL(a.Assets.LargeImage, a.ApplicationID, size)
+}
+
+// SmallImageURL resolves Assets.SmallImage into a CDN URL. size must be a power of two
+// between 16 and 4096; an invalid size falls back to a sensible default.
+func (a *UserActivity) SmallImageURL(size int) string {
+	if a.Assets == nil {
+		return ""
+	}
+	return resolveActivityAssetImageURL(a.Assets.SmallImage, a.ApplicationID, size)
+}
+
+// activityAlias avoids recursing into UserActivity's own (Un)MarshalJSON, and carries
+// CreatedAt as the unix-millisecond wire value Discord actually sends.
+type activityAlias struct {
+	Name          string               `json:"name"`
+	Type          ActivityType         `json:"type"`
+	URL           *string              `json:"url,omitempty"`
+	CreatedAt     int64                `json:"created_at,omitempty"`
+	Timestamps    []*ActivityTimestamp `json:"timestamps,omitempty"`
+	ApplicationID Snowflake            `json:"application_id,omitempty"`
+	Details       *string              `json:"details,omitempty"`
+	State         *string              `json:"state,omitempty"`
+	Emoji         *ActivityEmoji       `json:"emoji,omitempty"`
+	Party         *ActivityParty       `json:"party"`
+	Assets        *ActivityAssets      `json:"assets,omitempty"`
+	Secrets       *ActivitySecrets     `json:"secrets,omitempty"`
+	Instance      bool                 `json:"instance,omitempty"`
+	Flags         int                  `json:"flags,omitempty"`
+	Buttons       []ActivityButton     `json:"buttons,omitempty"`
+}
+
+func (a *UserActivity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&activityAlias{
+		Name:          a.Name,
+		Type:          a.Type,
+		URL:           a.URL,
+		CreatedAt:     timeToUnixMilli(a.CreatedAt),
+		Timestamps:    a.Timestamps,
+		ApplicationID: a.ApplicationID,
+		Details:       a.Details,
+		State:         a.State,
+		Emoji:         a.Emoji,
+		Party:         a.Party,
+		Assets:        a.Assets,
+		Secrets:       a.Secrets,
+		Instance:      a.Instance,
+		Flags:         a.Flags,
+		Buttons:       a.Buttons,
+	})
+}
+
+func (a *UserActivity) UnmarshalJSON(data []byte) error {
+	var v activityAlias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	a.Name = v.Name
+	a.Type = v.Type
+	a.URL = v.URL
+	a.CreatedAt = unixMilliToTime(v.CreatedAt)
+	a.Timestamps = v.Timestamps
+	a.ApplicationID = v.ApplicationID
+	a.Details = v.Details
+	a.State = v.State
+	a.Emoji = v.Emoji
+	a.Party = v.Party
+	a.Assets = v.Assets
+	a.Secrets = v.Secrets
+	a.Instance = v.Instance
+	a.Flags = v.Flags
+	a.Buttons = v.Buttons
+	return nil
+}
+
+// NewCustomActivity creates a type 4 "Custom Status" activity, e.g. "😄 Feeling good".
+func NewCustomActivity(text string, emoji *ActivityEmoji) *UserActivity {
+	state := text
+	return &UserActivity{
+		Name:  "Custom Status",
+		Type:  ActivityCustom,
+		State: &state,
+		Emoji: emoji,
+	}
+}
+
+// NewStreamingActivity creates a type 1 streaming activity. url must point to a
+// twitch.tv or youtube.com stream for Discord to render it as "Streaming".
+func NewStreamingActivity(name, url string) *UserActivity {
+	return &UserActivity{
+		Name: name,
+		Type: ActivityStreaming,
+		URL:  &url,
+	}
 }
 
 // ---------
 
+const (
+	cdnBaseURL = "https://cdn.discordapp.com"
+	// DefaultAvatarURL is the fallback avatar used when a user's discriminator can not
+	// be resolved to one of Discord's 5 default avatars.
+	DefaultAvatarURL = cdnBaseURL + "/embed/avatars/0.png"
+)
+
+// UserFlags is a bitmask of the public badges shown on a user's profile.
+// https://discordapp.com/developers/docs/resources/user#user-object-user-flags
+type UserFlags uint32
+
+const (
+	UserFlagNone                  UserFlags = 0
+	UserFlagStaff                 UserFlags = 1 << 0
+	UserFlagPartner               UserFlags = 1 << 1
+	UserFlagHypeSquad             UserFlags = 1 << 2
+	UserFlagBugHunterLevel1       UserFlags = 1 << 3
+	UserFlagHypeSquadOnlineHouse1 UserFlags = 1 << 6 // Bravery
+	UserFlagHypeSquadOnlineHouse2 UserFlags = 1 << 7 // Brilliance
+	UserFlagHypeSquadOnlineHouse3 UserFlags = 1 << 8 // Balance
+	UserFlagPremiumEarlySupporter UserFlags = 1 << 9
+	UserFlagTeamUser              UserFlags = 1 << 10
+	UserFlagSystem                UserFlags = 1 << 12
+	UserFlagBugHunterLevel2       UserFlags = 1 << 14
+	UserFlagVerifiedBot           UserFlags = 1 << 16
+	UserFlagVerifiedBotDeveloper  UserFlags = 1 << 17
+)
+
+// UserNitro is the Nitro subscription tier reported in User.Nitro (premium_type).
+type UserNitro int
+
+const (
+	UserNitroNone UserNitro = iota
+	UserNitroClassic
+	UserNitroFull
+)
+
 func NewUser() *User {
 	return &User{}
 }
 
+// User is kept as-is, exported fields and all, for source compatibility with existing
+// callers (user.Username = "x", struct literals, CopyOverTo, etc.). It has the same
+// embedded-sync.RWMutex footgun it always has: it is not safe to copy by value, and
+// concurrent field access is the caller's responsibility to guard. SafeUser is the
+// thread-safe replacement for it; User will be deprecated in favor of SafeUser after one
+// release cycle once callers have had a chance to migrate.
 type User struct {
 	ID            Snowflake `json:"id,omitempty"`
 	Username      string    `json:"username,omitempty"`
 	Discriminator string    `json:"discriminator,omitempty"`
 	Email         string    `json:"email,omitempty"`
 	Avatar        *string   `json:"avatar"` // data:image/jpeg;base64,BASE64_ENCODED_JPEG_IMAGE_DATA //TODO: pointer?
+	Banner        *string   `json:"banner,omitempty"`
 	Token         string    `json:"token,omitempty"`
 	Verified      bool      `json:"verified,omitempty"`
 	MFAEnabled    bool      `json:"mfa_enabled,omitempty"`
 	Bot           bool      `json:"bot,omitempty"`
+	Flags         UserFlags `json:"flags,omitempty"`
+	PublicFlags   UserFlags `json:"public_flags,omitempty"`
+	Nitro         UserNitro `json:"premium_type,omitempty"`
+	Locale        string    `json:"locale,omitempty"`
 
 	sync.RWMutex `json:"-"`
 }
 
+// HasFlag checks whether the user's Flags include the given badge.
+func (u *User) HasFlag(flag UserFlags) bool {
+	return u.Flags&flag == flag
+}
+
+// AvatarURL returns the CDN URL for the user's avatar, falling back to DefaultAvatarURL
+// when the user has none set.
+func (u *User) AvatarURL() string {
+	if u.Avatar == nil || *u.Avatar == "" {
+		return u.DefaultAvatarURL()
+	}
+	return cdnBaseURL + "/avatars/" + u.ID.String() + "/" + *u.Avatar + ".png"
+}
+
+// AvatarURLAnimated is like AvatarURL, but resolves to a .gif when the user's avatar is
+// animated (Discord prefixes animated avatar hashes with "a_").
+func (u *User) AvatarURLAnimated() string {
+	if u.Avatar == nil || *u.Avatar == "" {
+		return u.DefaultAvatarURL()
+	}
+
+	ext := "png"
+	if strings.HasPrefix(*u.Avatar, "a_") {
+		ext = "gif"
+	}
+	return cdnBaseURL + "/avatars/" + u.ID.String() + "/" + *u.Avatar + "." + ext
+}
+
+// DefaultAvatarURL returns one of Discord's 5 default avatars, selected by
+// discriminator modulo 5.
+func (u *User) DefaultAvatarURL() string {
+	discriminator, err := strconv.Atoi(u.Discriminator)
+	if err != nil {
+		return DefaultAvatarURL
+	}
+	return cdnBaseURL + "/embed/avatars/" + strconv.Itoa(discriminator%5) + ".png"
+}
+
+// BannerURL returns the CDN URL for the user's profile banner, or an empty string if
+// the user has none set.
+func (u *User) BannerURL() string {
+	if u.Banner == nil || *u.Banner == "" {
+		return ""
+	}
+	return cdnBaseURL + "/banners/" + u.ID.String() + "/" + *u.Banner + ".png"
+}
+
 func (u *User) Mention() string {
 	return "<@" + u.ID.String() + ">"
 }
@@ -184,11 +485,19 @@ func (u *User) CopyOverTo(other interface{}) (err error) {
 	user.Verified = u.Verified
 	user.MFAEnabled = u.MFAEnabled
 	user.Bot = u.Bot
+	user.Flags = u.Flags
+	user.PublicFlags = u.PublicFlags
+	user.Nitro = u.Nitro
+	user.Locale = u.Locale
 
 	if u.Avatar != nil {
 		avatar := *u.Avatar
 		user.Avatar = &avatar
 	}
+	if u.Banner != nil {
+		banner := *u.Banner
+		user.Banner = &banner
+	}
 
 	u.RUnlock()
 	user.Unlock()
@@ -208,6 +517,255 @@ func (u *User) Valid() bool {
 
 // -------
 
+func NewSafeUser() *SafeUser {
+	return &SafeUser{}
+}
+
+// userData holds every JSON field of a user. It is kept separate from SafeUser so that
+// the wire format never has to carry a mutex and json.Marshal never sees one. SafeUser
+// itself holds its sync.RWMutex by value, so it is not copyable either (go vet copylocks
+// flags copies of it); DeepCopy and CopyOverTo copy the data without the lock.
+type userData struct {
+	ID            Snowflake `json:"id,omitempty"`
+	Username      string    `json:"username,omitempty"`
+	Discriminator string    `json:"discriminator,omitempty"`
+	Email         string    `json:"email,omitempty"`
+	Avatar        *string   `json:"avatar"` // data:image/jpeg;base64,BASE64_ENCODED_JPEG_IMAGE_DATA //TODO: pointer?
+	Banner        *string   `json:"banner,omitempty"`
+	Token         string    `json:"token,omitempty"`
+	Verified      bool      `json:"verified,omitempty"`
+	MFAEnabled    bool      `json:"mfa_enabled,omitempty"`
+	Bot           bool      `json:"bot,omitempty"`
+	Flags         UserFlags `json:"flags,omitempty"`
+	PublicFlags   UserFlags `json:"public_flags,omitempty"`
+	Nitro         UserNitro `json:"premium_type,omitempty"`
+	Locale        string    `json:"locale,omitempty"`
+}
+
+// SafeUser is a thread-safe handle to a Discord user, shipped alongside User as the
+// migration path off of User's embedded sync.RWMutex footgun (it makes User non-copyable,
+// leaks into every json.Marshal call, and its lock is never actually taken by concurrent
+// readers of plain fields). All JSON fields live behind accessor methods (Username,
+// SetUsername, ...) that take the lock internally. d and mu are held by value, so the
+// zero value SafeUser{} is always safe to use without calling NewSafeUser() first.
+type SafeUser struct {
+	d  userData
+	mu sync.RWMutex
+}
+
+func (u *SafeUser) ID() Snowflake        { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.ID }
+func (u *SafeUser) SetID(v Snowflake)    { u.mu.Lock(); defer u.mu.Unlock(); u.d.ID = v }
+func (u *SafeUser) Username() string     { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.Username }
+func (u *SafeUser) SetUsername(v string) { u.mu.Lock(); defer u.mu.Unlock(); u.d.Username = v }
+func (u *SafeUser) Discriminator() string {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.d.Discriminator
+}
+func (u *SafeUser) SetDiscriminator(v string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.d.Discriminator = v
+}
+func (u *SafeUser) Email() string        { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.Email }
+func (u *SafeUser) SetEmail(v string)    { u.mu.Lock(); defer u.mu.Unlock(); u.d.Email = v }
+func (u *SafeUser) Avatar() *string      { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.Avatar }
+func (u *SafeUser) SetAvatar(v *string)  { u.mu.Lock(); defer u.mu.Unlock(); u.d.Avatar = v }
+func (u *SafeUser) Banner() *string      { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.Banner }
+func (u *SafeUser) SetBanner(v *string)  { u.mu.Lock(); defer u.mu.Unlock(); u.d.Banner = v }
+func (u *SafeUser) Token() string        { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.Token }
+func (u *SafeUser) SetToken(v string)    { u.mu.Lock(); defer u.mu.Unlock(); u.d.Token = v }
+func (u *SafeUser) Verified() bool       { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.Verified }
+func (u *SafeUser) SetVerified(v bool)   { u.mu.Lock(); defer u.mu.Unlock(); u.d.Verified = v }
+func (u *SafeUser) MFAEnabled() bool     { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.MFAEnabled }
+func (u *SafeUser) SetMFAEnabled(v bool) { u.mu.Lock(); defer u.mu.Unlock(); u.d.MFAEnabled = v }
+func (u *SafeUser) Bot() bool            { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.Bot }
+func (u *SafeUser) SetBot(v bool)        { u.mu.Lock(); defer u.mu.Unlock(); u.d.Bot = v }
+func (u *SafeUser) Flags() UserFlags     { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.Flags }
+func (u *SafeUser) SetFlags(v UserFlags) { u.mu.Lock(); defer u.mu.Unlock(); u.d.Flags = v }
+func (u *SafeUser) PublicFlags() UserFlags {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.d.PublicFlags
+}
+func (u *SafeUser) SetPublicFlags(v UserFlags) { u.mu.Lock(); defer u.mu.Unlock(); u.d.PublicFlags = v }
+func (u *SafeUser) Nitro() UserNitro           { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.Nitro }
+func (u *SafeUser) SetNitro(v UserNitro)       { u.mu.Lock(); defer u.mu.Unlock(); u.d.Nitro = v }
+func (u *SafeUser) Locale() string             { u.mu.RLock(); defer u.mu.RUnlock(); return u.d.Locale }
+func (u *SafeUser) SetLocale(v string)         { u.mu.Lock(); defer u.mu.Unlock(); u.d.Locale = v }
+
+// HasFlag checks whether the user's Flags include the given badge.
+func (u *SafeUser) HasFlag(flag UserFlags) bool {
+	return u.Flags()&flag == flag
+}
+
+// AvatarURL returns the CDN URL for the user's avatar, falling back to DefaultAvatarURL
+// when the user has none set.
+func (u *SafeUser) AvatarURL() string {
+	avatar := u.Avatar()
+	if avatar == nil || *avatar == "" {
+		return u.DefaultAvatarURL()
+	}
+	return cdnBaseURL + "/avatars/" + u.ID().String() + "/" + *avatar + ".png"
+}
+
+// AvatarURLAnimated is like AvatarURL, but resolves to a .gif when the user's avatar is
+// animated (Discord prefixes animated avatar hashes with "a_").
+func (u *SafeUser) AvatarURLAnimated() string {
+	avatar := u.Avatar()
+	if avatar == nil || *avatar == "" {
+		return u.DefaultAvatarURL()
+	}
+
+	ext := "png"
+	if strings.HasPrefix(*avatar, "a_") {
+		ext = "gif"
+	}
+	return cdnBaseURL + "/avatars/" + u.ID().String() + "/" + *avatar + "." + ext
+}
+
+// DefaultAvatarURL returns one of Discord's 5 default avatars, selected by
+// discriminator modulo 5.
+func (u *SafeUser) DefaultAvatarURL() string {
+	discriminator, err := strconv.Atoi(u.Discriminator())
+	if err != nil {
+		return DefaultAvatarURL
+	}
+	return cdnBaseURL + "/embed/avatars/" + strconv.Itoa(discriminator%5) + ".png"
+}
+
+// BannerURL returns the CDN URL for the user's profile banner, or an empty string if
+// the user has none set.
+func (u *SafeUser) BannerURL() string {
+	banner := u.Banner()
+	if banner == nil || *banner == "" {
+		return ""
+	}
+	return cdnBaseURL + "/banners/" + u.ID().String() + "/" + *banner + ".png"
+}
+
+func (u *SafeUser) Mention() string {
+	return "<@" + u.ID().String() + ">"
+}
+
+func (u *SafeUser) MentionNickname() string {
+	return "<@!" + u.ID().String() + ">"
+}
+
+func (u *SafeUser) String() string {
+	return u.Username() + "#" + u.Discriminator() + "{" + u.ID().String() + "}"
+}
+
+// Partial check if this is not a complete user object
+// Assumption: has a snowflake.
+func (u *SafeUser) Partial() bool {
+	return (u.Username() + u.Discriminator()) == ""
+}
+
+func (u *SafeUser) MarshalJSON() ([]byte, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	if u.d.ID.Empty() {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(&u.d)
+}
+
+func (u *SafeUser) UnmarshalJSON(data []byte) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return json.Unmarshal(data, &u.d)
+}
+
+func (u *SafeUser) Clear() {
+	u.SetAvatar(nil)
+}
+
+func (u *SafeUser) SendMsg(session Session, message *Message) (channel *Channel, msg *Message, err error) {
+	channel, err = session.CreateDM(u.ID())
+	if err != nil {
+		return
+	}
+
+	msg, err = session.SendMsg(channel.ID, message)
+	return
+}
+
+func (u *SafeUser) SendMsgString(session Session, content string) (channel *Channel, msg *Message, err error) {
+	channel, msg, err = u.SendMsg(session, &Message{
+		Content: content,
+	})
+	return
+}
+
+func (u *SafeUser) DeepCopy() (copy interface{}) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	d := u.d
+	if u.d.Avatar != nil {
+		avatar := *u.d.Avatar
+		d.Avatar = &avatar
+	}
+	if u.d.Banner != nil {
+		banner := *u.d.Banner
+		d.Banner = &banner
+	}
+
+	return &SafeUser{d: d}
+}
+
+func (u *SafeUser) CopyOverTo(other interface{}) (err error) {
+	var user *SafeUser
+	var valid bool
+	if user, valid = other.(*SafeUser); !valid {
+		err = NewErrorUnsupportedType("argument given is not a *SafeUser type")
+		return
+	}
+
+	cp := u.DeepCopy().(*SafeUser)
+
+	user.mu.Lock()
+	user.d = cp.d
+	user.mu.Unlock()
+
+	return
+}
+
+func (u *SafeUser) SaveToDiscord(session Session) (err error) {
+	// TODO: check snowflake if ID is current user
+	// call both modify methods
+	return errors.New("not implemented")
+}
+
+func (u *SafeUser) Valid() bool {
+	return u.ID() > 0
+}
+
+// -------
+
+// ClientStatus holds the platform a user is active on. A missing/empty field means the
+// user is not active on that platform, per the gateway presence update documentation.
+type ClientStatus struct {
+	Desktop string `json:"desktop,omitempty"`
+	Mobile  string `json:"mobile,omitempty"`
+	Web     string `json:"web,omitempty"`
+}
+
+// UpdateStatusPayload describes a Gateway Presence Update op, as sent by the client to
+// change its own status, activities and AFK state.
+// https://discordapp.com/developers/docs/topics/gateway#update-status
+type UpdateStatusPayload struct {
+	Since        *int            `json:"since"`
+	Activities   []*UserActivity `json:"activities"`
+	Status       string          `json:"status"`
+	AFK          bool            `json:"afk"`
+	ClientStatus ClientStatus    `json:"-"` // applied to the local UserPresence only; Discord assigns client_status per-session and ignores it on this op
+}
+
 func NewUserPresence() *UserPresence {
 	return &UserPresence{}
 }
@@ -215,15 +773,71 @@ func NewUserPresence() *UserPresence {
 type UserPresence struct {
 	User    *User         `json:"user"`
 	Roles   []Snowflake   `json:"roles"`
-	Game    *UserActivity `json:"activity"`
+	Game    *UserActivity `json:"activity"` // deprecated by Discord; kept in sync with Activities[0]
 	GuildID Snowflake     `json:"guild_id"`
 	Nick    string        `json:"nick"`
 	Status  string        `json:"status"`
+
+	Activities   []*UserActivity `json:"activities"`
+	PremiumSince *time.Time      `json:"premium_since,omitempty"`
+	ClientStatus ClientStatus    `json:"client_status"`
 }
 
-func (p *UserPresence) Update(status string) {
-	// Update the presence.
-	// talk to the discord api
+// presenceAlias avoids recursing into UserPresence's own (Un)MarshalJSON while still
+// going through encoding/json for every other field.
+type presenceAlias UserPresence
+
+// MarshalJSON reconciles Game and Activities before writing the payload, since Discord
+// expects the legacy "activity" field to mirror the first entry of "activities".
+func (p *UserPresence) MarshalJSON() ([]byte, error) {
+	alias := presenceAlias(*p)
+	if alias.Game == nil && len(alias.Activities) > 0 {
+		alias.Game = alias.Activities[0]
+	}
+	if len(alias.Activities) == 0 && alias.Game != nil {
+		alias.Activities = []*UserActivity{alias.Game}
+	}
+
+	return json.Marshal(alias)
+}
+
+// UnmarshalJSON reconciles Game and Activities after reading the payload; Discord only
+// guarantees one of the two fields is present depending on gateway version.
+func (p *UserPresence) UnmarshalJSON(data []byte) error {
+	var alias presenceAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+
+	if len(alias.Activities) == 0 && alias.Game != nil {
+		alias.Activities = []*UserActivity{alias.Game}
+	} else if alias.Game == nil && len(alias.Activities) > 0 {
+		alias.Game = alias.Activities[0]
+	}
+
+	*p = UserPresence(alias)
+	return nil
+}
+
+// Update replaces this presence's status, activities and per-client status with the
+// ones described by payload, and sets Game to the first activity (nil when there are
+// none). It only changes the local UserPresence; nothing is sent to Discord.
+func (p *UserPresence) Update(payload *UpdateStatusPayload) error {
+	if payload == nil {
+		return errors.New("payload can not be nil")
+	}
+
+	p.Status = payload.Status
+	p.Activities = payload.Activities
+	p.ClientStatus = payload.ClientStatus
+	if len(payload.Activities) > 0 {
+		p.Game = payload.Activities[0]
+	} else {
+		p.Game = nil
+	}
+
+	// TODO: send payload over the gateway connection
+	return nil
 }
 
 func (p *UserPresence) String() string {
@@ -232,6 +846,8 @@ func (p *UserPresence) String() string {
 
 func (p *UserPresence) Clear() {
 	p.Game = nil
+	p.Activities = nil
+	p.ClientStatus = ClientStatus{}
 }
 
 type UserConnection struct {
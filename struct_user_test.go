@@ -0,0 +1,384 @@
+package disgord
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUserPresenceMarshalReconcilesGameAndActivities(t *testing.T) {
+	game := &UserActivity{Name: "Celeste"}
+
+	p := &UserPresence{Game: game}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Unmarshal(raw) error = %v", err)
+	}
+
+	activities, _ := raw["activities"].([]interface{})
+	if len(activities) != 1 {
+		t.Fatalf("expected 1 activity in marshaled output, got %d", len(activities))
+	}
+}
+
+func TestUserPresenceUnmarshalReconcilesGameAndActivities(t *testing.T) {
+	data := []byte(`{"activities":[{"name":"Celeste","type":0}]}`)
+
+	var p UserPresence
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if p.Game == nil {
+		t.Fatal("expected Game to be populated from Activities[0], got nil")
+	}
+	if p.Game.Name != "Celeste" {
+		t.Errorf("Game.Name = %q, want %q", p.Game.Name, "Celeste")
+	}
+
+	legacy := []byte(`{"activity":{"name":"Old Client","type":0}}`)
+	var p2 UserPresence
+	if err := json.Unmarshal(legacy, &p2); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(p2.Activities) != 1 || p2.Activities[0].Name != "Old Client" {
+		t.Errorf("Activities not populated from legacy Game field: %+v", p2.Activities)
+	}
+}
+
+func TestUserPresenceUpdate(t *testing.T) {
+	activity := &UserActivity{Name: "Celeste"}
+	payload := &UpdateStatusPayload{
+		Status:     StatusOnline,
+		Activities: []*UserActivity{activity},
+		ClientStatus: ClientStatus{
+			Desktop: StatusOnline,
+			Mobile:  StatusIdle,
+		},
+	}
+
+	p := &UserPresence{}
+	if err := p.Update(payload); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if p.Status != StatusOnline {
+		t.Errorf("Status = %q, want %q", p.Status, StatusOnline)
+	}
+	if len(p.Activities) != 1 || p.Activities[0] != activity {
+		t.Errorf("Activities = %+v, want [%+v]", p.Activities, activity)
+	}
+	if p.Game != activity {
+		t.Errorf("Game = %+v, want %+v", p.Game, activity)
+	}
+	if p.ClientStatus != payload.ClientStatus {
+		t.Errorf("ClientStatus = %+v, want %+v", p.ClientStatus, payload.ClientStatus)
+	}
+}
+
+func TestUserPresenceUpdateNilPayload(t *testing.T) {
+	p := &UserPresence{}
+	if err := p.Update(nil); err == nil {
+		t.Error("Update(nil) error = nil, want an error")
+	}
+}
+
+func TestUserPresenceClearResetsActivitiesAndClientStatus(t *testing.T) {
+	p := &UserPresence{
+		Game:         &UserActivity{Name: "x"},
+		Activities:   []*UserActivity{{Name: "x"}},
+		ClientStatus: ClientStatus{Desktop: StatusOnline},
+	}
+
+	p.Clear()
+
+	if p.Game != nil {
+		t.Errorf("Game = %+v, want nil", p.Game)
+	}
+	if p.Activities != nil {
+		t.Errorf("Activities = %+v, want nil", p.Activities)
+	}
+	if p.ClientStatus != (ClientStatus{}) {
+		t.Errorf("ClientStatus = %+v, want zero value", p.ClientStatus)
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Unmarshal(raw) error = %v", err)
+	}
+	if raw["activity"] != nil {
+		t.Errorf("marshaled payload still has a non-null \"activity\": %s", data)
+	}
+}
+
+func TestActivityTimestampMillisRoundTrip(t *testing.T) {
+	start := time.Unix(1_600_000_000, 500_000_000)
+
+	ts := &ActivityTimestamp{Start: start}
+	data, err := json.Marshal(ts)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got ActivityTimestamp
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !got.Start.Equal(start.Round(time.Millisecond)) {
+		t.Errorf("Start = %v, want %v", got.Start, start.Round(time.Millisecond))
+	}
+	if !got.End.IsZero() {
+		t.Errorf("End = %v, want zero time", got.End)
+	}
+}
+
+func TestUserActivityCreatedAtRoundTrip(t *testing.T) {
+	created := time.Unix(1_650_000_000, 0)
+
+	a := &UserActivity{Name: "Test", CreatedAt: created}
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got UserActivity
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !got.CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, created)
+	}
+}
+
+func TestNewCustomActivity(t *testing.T) {
+	emoji := &ActivityEmoji{Name: "smile"}
+	a := NewCustomActivity("feeling good", emoji)
+
+	if a.Type != ActivityCustom {
+		t.Errorf("Type = %v, want %v", a.Type, ActivityCustom)
+	}
+	if a.State == nil || *a.State != "feeling good" {
+		t.Errorf("State = %v, want %q", a.State, "feeling good")
+	}
+	if a.Emoji != emoji {
+		t.Errorf("Emoji = %v, want %v", a.Emoji, emoji)
+	}
+}
+
+func TestNewStreamingActivity(t *testing.T) {
+	a := NewStreamingActivity("Just Chatting", "https://twitch.tv/someone")
+
+	if a.Type != ActivityStreaming {
+		t.Errorf("Type = %v, want %v", a.Type, ActivityStreaming)
+	}
+	if a.URL == nil || *a.URL != "https://twitch.tv/someone" {
+		t.Errorf("URL = %v, want %q", a.URL, "https://twitch.tv/someone")
+	}
+}
+
+func TestUserAvatarURL(t *testing.T) {
+	u := NewUser()
+	u.ID = 123
+	u.Discriminator = "0007"
+
+	hash := "abc123"
+	u.Avatar = &hash
+	want := "https://cdn.discordapp.com/avatars/123/abc123.png"
+	if got := u.AvatarURL(); got != want {
+		t.Errorf("AvatarURL() = %q, want %q", got, want)
+	}
+
+	u.Avatar = nil
+	if got := u.AvatarURL(); got != u.DefaultAvatarURL() {
+		t.Errorf("AvatarURL() with no avatar = %q, want %q", got, u.DefaultAvatarURL())
+	}
+}
+
+func TestUserAvatarURLAnimated(t *testing.T) {
+	u := NewUser()
+	u.ID = 123
+
+	animated := "a_abc123"
+	u.Avatar = &animated
+	want := "https://cdn.discordapp.com/avatars/123/a_abc123.gif"
+	if got := u.AvatarURLAnimated(); got != want {
+		t.Errorf("AvatarURLAnimated() = %q, want %q", got, want)
+	}
+
+	static := "abc123"
+	u.Avatar = &static
+	want = "https://cdn.discordapp.com/avatars/123/abc123.png"
+	if got := u.AvatarURLAnimated(); got != want {
+		t.Errorf("AvatarURLAnimated() = %q, want %q", got, want)
+	}
+}
+
+func TestUserDefaultAvatarURL(t *testing.T) {
+	u := NewUser()
+	u.Discriminator = "0007"
+	want := "https://cdn.discordapp.com/embed/avatars/2.png"
+	if got := u.DefaultAvatarURL(); got != want {
+		t.Errorf("DefaultAvatarURL() = %q, want %q", got, want)
+	}
+
+	u.Discriminator = "not-a-number"
+	if got := u.DefaultAvatarURL(); got != DefaultAvatarURL {
+		t.Errorf("DefaultAvatarURL() with non-numeric discriminator = %q, want %q", got, DefaultAvatarURL)
+	}
+}
+
+func TestUserBannerURL(t *testing.T) {
+	u := NewUser()
+	u.ID = 123
+
+	if got := u.BannerURL(); got != "" {
+		t.Errorf("BannerURL() with no banner = %q, want empty", got)
+	}
+
+	banner := "banner123"
+	u.Banner = &banner
+	want := "https://cdn.discordapp.com/banners/123/banner123.png"
+	if got := u.BannerURL(); got != want {
+		t.Errorf("BannerURL() = %q, want %q", got, want)
+	}
+}
+
+func TestUserHasFlag(t *testing.T) {
+	u := NewUser()
+	u.Flags = UserFlagStaff | UserFlagVerifiedBot
+
+	if !u.HasFlag(UserFlagStaff) {
+		t.Error("HasFlag(UserFlagStaff) = false, want true")
+	}
+	if u.HasFlag(UserFlagPartner) {
+		t.Error("HasFlag(UserFlagPartner) = true, want false")
+	}
+}
+
+func TestUserZeroValueIsSafe(t *testing.T) {
+	var u User
+	u.Username = "zero"
+	if u.Username != "zero" {
+		t.Errorf("Username = %q, want %q", u.Username, "zero")
+	}
+	if u.Valid() {
+		t.Error("Valid() = true for a user with no ID, want false")
+	}
+}
+
+func TestSafeUserAvatarURL(t *testing.T) {
+	u := NewSafeUser()
+	u.SetID(123)
+	u.SetDiscriminator("0007")
+
+	hash := "abc123"
+	u.SetAvatar(&hash)
+	want := "https://cdn.discordapp.com/avatars/123/abc123.png"
+	if got := u.AvatarURL(); got != want {
+		t.Errorf("AvatarURL() = %q, want %q", got, want)
+	}
+
+	u.SetAvatar(nil)
+	if got := u.AvatarURL(); got != u.DefaultAvatarURL() {
+		t.Errorf("AvatarURL() with no avatar = %q, want %q", got, u.DefaultAvatarURL())
+	}
+}
+
+func TestSafeUserHasFlag(t *testing.T) {
+	u := NewSafeUser()
+	u.SetFlags(UserFlagStaff | UserFlagVerifiedBot)
+
+	if !u.HasFlag(UserFlagStaff) {
+		t.Error("HasFlag(UserFlagStaff) = false, want true")
+	}
+	if u.HasFlag(UserFlagPartner) {
+		t.Error("HasFlag(UserFlagPartner) = true, want false")
+	}
+}
+
+func TestSafeUserZeroValueIsSafe(t *testing.T) {
+	var u SafeUser
+	u.SetUsername("zero")
+	if u.Username() != "zero" {
+		t.Errorf("Username() = %q, want %q", u.Username(), "zero")
+	}
+	if u.Valid() {
+		t.Error("Valid() = true for a user with no ID, want false")
+	}
+}
+
+func TestActivityImageURLSchemes(t *testing.T) {
+	tests := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"snowflake", "icon123", "https://cdn.discordapp.com/app-assets/42/icon123.png?size=1024"},
+		{"mediaproxy", "mp:foo/bar.png", "https://media.discordapp.net/foo/bar.png"},
+		{"spotify", "spotify:abcdef", "https://i.scdn.co/image/abcdef"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &UserActivity{
+				ApplicationID: 42,
+				Assets:        &ActivityAssets{LargeImage: tt.image},
+			}
+			if got := a.LargeImageURL(defaultActivityAssetImageSize); got != tt.want {
+				t.Errorf("LargeImageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivityImageURLSizeFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		want int
+	}{
+		{"valid power of two", 256, 256},
+		{"too small", 8, defaultActivityAssetImageSize},
+		{"too large", 8192, defaultActivityAssetImageSize},
+		{"not a power of two", 300, defaultActivityAssetImageSize},
+		{"zero", 0, defaultActivityAssetImageSize},
+		{"negative", -16, defaultActivityAssetImageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &UserActivity{
+				ApplicationID: 42,
+				Assets:        &ActivityAssets{LargeImage: "icon123"},
+			}
+			want := "https://cdn.discordapp.com/app-assets/42/icon123.png?size=" + strconv.Itoa(tt.want)
+			if got := a.LargeImageURL(tt.size); got != want {
+				t.Errorf("LargeImageURL(%d) = %q, want %q", tt.size, got, want)
+			}
+		})
+	}
+}
+
+func TestActivityImageURLNoAssets(t *testing.T) {
+	a := &UserActivity{ApplicationID: 42}
+	if got := a.LargeImageURL(defaultActivityAssetImageSize); got != "" {
+		t.Errorf("LargeImageURL() with nil Assets = %q, want empty", got)
+	}
+	if got := a.SmallImageURL(defaultActivityAssetImageSize); got != "" {
+		t.Errorf("SmallImageURL() with nil Assets = %q, want empty", got)
+	}
+}